@@ -0,0 +1,85 @@
+package webpage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Action represents a single browser interaction that can be run against a
+// Session after navigation and before a capture.
+type Action interface {
+	Do(ctx context.Context) error
+}
+
+// actionFunc adapts a plain function into an Action.
+type actionFunc func(ctx context.Context) error
+
+// Do runs the action.
+func (f actionFunc) Do(ctx context.Context) error { return f(ctx) }
+
+// Click clicks the first element matching selector.
+func Click(selector string) Action {
+	return actionFunc(func(ctx context.Context) error {
+		return chromedp.Click(selector, chromedp.NodeVisible).Do(ctx)
+	})
+}
+
+// Type sends text as keystrokes to the first element matching selector.
+func Type(selector, text string) Action {
+	return actionFunc(func(ctx context.Context) error {
+		return chromedp.SendKeys(selector, text, chromedp.NodeVisible).Do(ctx)
+	})
+}
+
+// ScrollBy scrolls the page by (dx, dy) pixels.
+func ScrollBy(dx, dy int) Action {
+	return actionFunc(func(ctx context.Context) error {
+		return chromedp.Evaluate(fmt.Sprintf("window.scrollBy(%d, %d)", dx, dy), nil).Do(ctx)
+	})
+}
+
+// WaitVisible blocks until the first element matching selector is visible.
+func WaitVisible(selector string) Action {
+	return actionFunc(func(ctx context.Context) error {
+		return chromedp.WaitVisible(selector, chromedp.ByQuery).Do(ctx)
+	})
+}
+
+// Sleep pauses for d before running the next action.
+func Sleep(d time.Duration) Action {
+	return actionFunc(func(ctx context.Context) error {
+		return chromedp.Sleep(d).Do(ctx)
+	})
+}
+
+// Eval evaluates js in the page and discards the result.
+func Eval(js string) Action {
+	return actionFunc(func(ctx context.Context) error {
+		return chromedp.Evaluate(js, nil).Do(ctx)
+	})
+}
+
+// SetViewport resizes the browser viewport to (width, height).
+func SetViewport(width, height int64) Action {
+	return actionFunc(func(ctx context.Context) error {
+		return chromedp.EmulateViewport(width, height).Do(ctx)
+	})
+}
+
+// SetExtraHeaders sets extra HTTP headers sent with subsequent requests.
+// Since actions run after the initial navigation, these headers apply to
+// requests an action triggers (a later navigation, an XHR/fetch), not to
+// the page's first document request.
+func SetExtraHeaders(headers map[string]string) Action {
+	return actionFunc(func(ctx context.Context) error {
+		h := make(network.Headers, len(headers))
+		for k, v := range headers {
+			h[k] = v
+		}
+		return network.SetExtraHTTPHeaders(h).Do(ctx)
+	})
+}
@@ -3,30 +3,55 @@ package main
 import (
 	"context"
 	"fmt"
+	"image"
+	"image/png"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/joeychilson/webpage"
+	"github.com/joeychilson/webpage/screentest"
 )
 
 var (
-	timeout      time.Duration
-	userAgent    string
-	output       string
-	landscape    bool
-	background   bool
-	scale        float64
-	paperWidth   float64
-	paperHeight  float64
-	marginTop    float64
-	marginBottom float64
-	marginLeft   float64
-	marginRight  float64
-	pageRanges   string
-	format       string
-	quality      int64
+	timeout           time.Duration
+	userAgent         string
+	output            string
+	landscape         bool
+	background        bool
+	scale             float64
+	paperWidth        float64
+	paperHeight       float64
+	marginTop         float64
+	marginBottom      float64
+	marginLeft        float64
+	marginRight       float64
+	pageRanges        string
+	format            string
+	quality           int64
+	colors            int
+	dither            string
+	outputFormat      string
+	fullPage          bool
+	viewportWidth     int64
+	viewportHeight    int64
+	maxTileHeight     int64
+	addr              string
+	maxConcurrent     int
+	requestTimeout    time.Duration
+	cacheDir          string
+	diffDir           string
+	threshold         int
+	headerTemplate    string
+	footerTemplate    string
+	preferCSSPageSize bool
+	emulateMedia      string
+	waitUntil         string
+	waitSelector      string
+	extraHeaders      []string
 )
 
 var rootCmd = &cobra.Command{
@@ -48,6 +73,13 @@ var pdfCmd = &cobra.Command{
 		if userAgent != "" {
 			opts = append(opts, webpage.WithUserAgent(userAgent))
 		}
+		if len(extraHeaders) > 0 {
+			headers, err := parseHeaders(extraHeaders)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, webpage.WithExtraHTTPHeaders(headers))
+		}
 
 		page := webpage.New(url, opts...)
 
@@ -61,10 +93,32 @@ var pdfCmd = &cobra.Command{
 			webpage.WithMarginBottom(marginBottom),
 			webpage.WithMarginLeft(marginLeft),
 			webpage.WithMarginRight(marginRight),
+			webpage.WithPreferCSSPageSize(preferCSSPageSize),
 		}
 		if pageRanges != "" {
 			pdfOpts = append(pdfOpts, webpage.WithPageRanges(pageRanges))
 		}
+		if headerTemplate != "" {
+			pdfOpts = append(pdfOpts, webpage.WithHeaderTemplate(headerTemplate))
+		}
+		if footerTemplate != "" {
+			pdfOpts = append(pdfOpts, webpage.WithFooterTemplate(footerTemplate))
+		}
+		if emulateMedia != "" {
+			pdfOpts = append(pdfOpts, webpage.WithEmulateMedia(emulateMedia))
+		}
+		if waitSelector != "" {
+			pdfOpts = append(pdfOpts, webpage.WithWaitSelector(waitSelector))
+		}
+		switch waitUntil {
+		case "networkidle":
+			pdfOpts = append(pdfOpts, webpage.WithWaitUntil(webpage.NetworkIdle))
+		case "domcontentloaded":
+			pdfOpts = append(pdfOpts, webpage.WithWaitUntil(webpage.DOMContentLoaded))
+		case "load", "":
+		default:
+			return fmt.Errorf("invalid wait-until mode: %q", waitUntil)
+		}
 
 		pdf, err := page.PDF(context.Background(), pdfOpts...)
 		if err != nil {
@@ -100,6 +154,45 @@ var screenshotCmd = &cobra.Command{
 			webpage.WithFormat(format),
 			webpage.WithQuality(quality),
 		}
+		if colors > 0 {
+			screenshotOpts = append(screenshotOpts, webpage.WithColors(colors))
+		}
+		if outputFormat != "" {
+			screenshotOpts = append(screenshotOpts, webpage.WithOutputFormat(webpage.OutputFormat(outputFormat)))
+		}
+		switch dither {
+		case "floyd-steinberg":
+			screenshotOpts = append(screenshotOpts, webpage.WithDither(webpage.FloydSteinberg))
+		case "halftone":
+			screenshotOpts = append(screenshotOpts, webpage.WithDither(webpage.Halftone))
+		case "none", "":
+		default:
+			return fmt.Errorf("invalid dither mode: %q", dither)
+		}
+		if fullPage || viewportWidth > 0 || viewportHeight > 0 {
+			screenshotOpts = append(screenshotOpts, webpage.WithViewport(viewportWidth, viewportHeight))
+		}
+		if fullPage {
+			screenshotOpts = append(screenshotOpts, webpage.WithFullPage(true))
+		}
+		if maxTileHeight > 0 {
+			screenshotOpts = append(screenshotOpts, webpage.WithMaxTileHeight(maxTileHeight))
+		}
+
+		if maxTileHeight > 0 {
+			tiles, err := page.Screenshots(context.Background(), screenshotOpts...)
+			if err != nil {
+				return fmt.Errorf("failed to capture screenshot: %w", err)
+			}
+			for i, tile := range tiles {
+				path := fmt.Sprintf("%s.%d", output, i)
+				if err := os.WriteFile(path, tile, 0644); err != nil {
+					return fmt.Errorf("failed to write screenshot tile: %w", err)
+				}
+				fmt.Printf("Screenshot tile saved to: %s\n", path)
+			}
+			return nil
+		}
 
 		screenshot, err := page.Screenshot(context.Background(), screenshotOpts...)
 		if err != nil {
@@ -115,6 +208,115 @@ var screenshotCmd = &cobra.Command{
 	},
 }
 
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP server exposing /pdf and /screenshot endpoints",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		session, err := webpage.NewSession(context.Background(), webpage.WithTimeout(timeout))
+		if err != nil {
+			return fmt.Errorf("failed to start browser session: %w", err)
+		}
+		defer session.Close()
+
+		server := webpage.NewServer(session,
+			webpage.WithMaxConcurrent(maxConcurrent),
+			webpage.WithRequestTimeout(requestTimeout),
+		)
+
+		fmt.Printf("Listening on %s\n", addr)
+		return server.ListenAndServe(addr)
+	},
+}
+
+var testCmd = &cobra.Command{
+	Use:   "test [script]",
+	Short: "Run a script-driven visual regression test",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open test script: %w", err)
+		}
+		defer f.Close()
+
+		cases, err := screentest.Parse(f)
+		if err != nil {
+			return fmt.Errorf("failed to parse test script: %w", err)
+		}
+
+		session, err := webpage.NewSession(context.Background(), webpage.WithTimeout(timeout))
+		if err != nil {
+			return fmt.Errorf("failed to start browser session: %w", err)
+		}
+		defer session.Close()
+
+		runnerOpts := []screentest.RunnerOption{screentest.WithThreshold(threshold)}
+		if cacheDir != "" {
+			runnerOpts = append(runnerOpts, screentest.WithCacheDir(cacheDir))
+		}
+
+		results, err := screentest.NewRunner(session, runnerOpts...).Run(context.Background(), cases)
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		for i, result := range results {
+			status := "PASS"
+			if !result.Passed() {
+				status = "FAIL"
+				failed++
+				if diffDir != "" && result.Diff != nil {
+					path := filepath.Join(diffDir, fmt.Sprintf("testcase-%d.png", i+1))
+					if err := writeDiffPNG(path, result.Diff); err != nil {
+						return err
+					}
+				}
+			}
+			if result.Err != nil {
+				fmt.Printf("%s  %s vs %s (%s)\n",
+					status, result.TestCase.Origins[0], result.TestCase.Origins[1], result.Err)
+				continue
+			}
+			fmt.Printf("%s  %s vs %s (%d changed pixels, threshold %d)\n",
+				status, result.TestCase.Origins[0], result.TestCase.Origins[1], result.ChangedPixels, result.Threshold)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d test cases failed", failed, len(results))
+		}
+		return nil
+	},
+}
+
+func parseHeaders(raw []string) (map[string]string, error) {
+	headers := make(map[string]string, len(raw))
+	for _, h := range raw {
+		k, v, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header %q, want 'Key: Value'", h)
+		}
+		headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return headers, nil
+}
+
+func writeDiffPNG(path string, img *image.RGBA) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create diff directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create diff file: %w", err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("failed to encode diff image: %w", err)
+	}
+	return nil
+}
+
 func init() {
 	// browser flags
 	rootCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "t", 30*time.Second, "timeout for the operation")
@@ -132,13 +334,39 @@ func init() {
 	pdfCmd.Flags().Float64Var(&marginLeft, "margin-left", 0.4, "left margin in inches")
 	pdfCmd.Flags().Float64Var(&marginRight, "margin-right", 0.4, "right margin in inches")
 	pdfCmd.Flags().StringVar(&pageRanges, "pages", "", "page ranges to print (e.g., '1-5, 8, 11-13')")
+	pdfCmd.Flags().StringVar(&headerTemplate, "header-template", "", "HTML template for the page header")
+	pdfCmd.Flags().StringVar(&footerTemplate, "footer-template", "", "HTML template for the page footer")
+	pdfCmd.Flags().BoolVar(&preferCSSPageSize, "prefer-css-page-size", false, "honor @page size declared in the page's CSS")
+	pdfCmd.Flags().StringVar(&emulateMedia, "emulate-media", "", "emulate a CSS media type before printing (print or screen)")
+	pdfCmd.Flags().StringVar(&waitUntil, "wait-until", "load", "when navigation is considered complete (load, domcontentloaded, networkidle)")
+	pdfCmd.Flags().StringVar(&waitSelector, "wait-selector", "", "wait for a selector to be visible before printing")
+	pdfCmd.Flags().StringArrayVar(&extraHeaders, "header", nil, "extra HTTP header to send with every request, as 'Key: Value' (repeatable)")
 
 	// screenshot flags
 	screenshotCmd.Flags().StringVarP(&format, "format", "f", "png", "screenshot format (png or jpeg)")
 	screenshotCmd.Flags().Int64VarP(&quality, "quality", "q", 100, "image quality (0-100, only for jpeg)")
+	screenshotCmd.Flags().IntVar(&colors, "colors", 0, "reduce the screenshot to a palette of at most n colors (2-256)")
+	screenshotCmd.Flags().StringVar(&dither, "dither", "none", "dithering mode when reducing colors (none, floyd-steinberg, halftone)")
+	screenshotCmd.Flags().StringVar(&outputFormat, "output-format", "", "override the screenshot encoding (png, jpeg, gif)")
+	screenshotCmd.Flags().BoolVar(&fullPage, "full-page", false, "capture the entire document height instead of a single viewport")
+	screenshotCmd.Flags().Int64Var(&viewportWidth, "viewport-width", 0, "viewport width in pixels (0 uses Chrome's default, or 1280 with --full-page or --max-tile-height)")
+	screenshotCmd.Flags().Int64Var(&viewportHeight, "viewport-height", 0, "viewport height in pixels (0 uses Chrome's default)")
+	screenshotCmd.Flags().Int64Var(&maxTileHeight, "max-tile-height", 0, "split captures taller than this into vertically stacked tiles (0 disables tiling)")
+
+	// serve flags
+	serveCmd.Flags().StringVar(&addr, "addr", ":8080", "address to listen on")
+	serveCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", webpage.DefaultMaxConcurrent, "maximum number of renders to run at once")
+	serveCmd.Flags().DurationVar(&requestTimeout, "request-timeout", webpage.DefaultRequestTimeout, "per-request render timeout")
+
+	// test flags
+	testCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "directory to read/write baseline captures suffixed with '::cache'")
+	testCmd.Flags().StringVar(&diffDir, "diff-dir", "", "directory to write per-testcase diff PNGs for failures")
+	testCmd.Flags().IntVar(&threshold, "threshold", screentest.DefaultThreshold, "number of changed pixels a test case may have before it's reported as failing")
 
 	rootCmd.AddCommand(pdfCmd)
 	rootCmd.AddCommand(screenshotCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(testCmd)
 }
 
 func main() {
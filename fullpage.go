@@ -0,0 +1,129 @@
+package webpage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// Screenshots returns the screenshot of the webpage as a slice of byte
+// slices, one per vertical tile. Rather than capturing the full page
+// height in one pass, it resizes the viewport and scrolls between
+// captures, so no single capture exceeds WithMaxTileHeight (or
+// maxSurfaceHeight if unset) pixels tall — keeping each capture under
+// Chrome's screenshot surface size limit and bounding peak memory to one
+// tile, even on very long pages. Pages within the limit are returned as
+// a single-element slice.
+func (w *Webpage) Screenshots(ctx context.Context, opts ...ScreenshotOption) ([][]byte, error) {
+	screenshotOpts := &ScreenshotOptions{
+		format:  "png",
+		quality: 100,
+	}
+	for _, opt := range opts {
+		opt(screenshotOpts)
+	}
+
+	maxHeight := screenshotOpts.maxTileHeight
+	if maxHeight == 0 {
+		maxHeight = maxSurfaceHeight
+	}
+	width := screenshotOpts.viewportWidth
+	if width == 0 {
+		width = defaultViewportWidth
+	}
+
+	execOpts := []chromedp.ExecAllocatorOption{
+		chromedp.DisableGPU,
+		chromedp.NoSandbox,
+		chromedp.Headless,
+	}
+	if w.browser.userAgent != "" {
+		execOpts = append(execOpts, chromedp.UserAgent(w.browser.userAgent))
+	}
+
+	allocCtx, cancel := chromedp.NewExecAllocator(ctx, execOpts...)
+	defer cancel()
+
+	taskCtx, cancel := chromedp.NewContext(allocCtx)
+	defer cancel()
+
+	timeoutCtx, cancel := context.WithTimeout(taskCtx, w.browser.timeout)
+	defer cancel()
+
+	var height int64
+	if err := chromedp.Run(timeoutCtx,
+		chromedp.Navigate(w.url),
+		chromedp.Evaluate(`document.body.scrollHeight`, &height),
+	); err != nil {
+		return nil, fmt.Errorf("failed to measure page height: %w", err)
+	}
+
+	var tiles [][]byte
+	for y := int64(0); y < height; y += maxHeight {
+		tileHeight := maxHeight
+		if remaining := height - y; remaining < tileHeight {
+			tileHeight = remaining
+		}
+
+		var buf []byte
+		tasks := chromedp.Tasks{
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				return emulation.SetDeviceMetricsOverride(width, tileHeight, 1, false).Do(ctx)
+			}),
+			chromedp.Evaluate(fmt.Sprintf(`window.scrollTo(0, %d)`, y), nil),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				var err error
+				buf, err = page.CaptureScreenshot().
+					WithFromSurface(true).
+					WithFormat(page.CaptureScreenshotFormat(screenshotOpts.format)).
+					WithQuality(screenshotOpts.quality).
+					Do(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to capture tile: %w", err)
+				}
+				return nil
+			}),
+		}
+		if err := chromedp.Run(timeoutCtx, tasks); err != nil {
+			return nil, fmt.Errorf("failed to capture tile at offset %d: %w", y, err)
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(buf))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tile: %w", err)
+		}
+		encoded, err := encodeTile(img, screenshotOpts)
+		if err != nil {
+			return nil, err
+		}
+		tiles = append(tiles, encoded)
+	}
+	return tiles, nil
+}
+
+// encodeTile encodes a single tile, applying quantization if requested.
+func encodeTile(img image.Image, opts *ScreenshotOptions) ([]byte, error) {
+	if opts.colors > 0 || opts.outputFormat != "" {
+		return encodeImage(img, opts)
+	}
+
+	var buf bytes.Buffer
+	switch opts.format {
+	case "jpeg":
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: int(opts.quality)}); err != nil {
+			return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+	default:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode png: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
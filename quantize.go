@@ -0,0 +1,127 @@
+package webpage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/soniakeys/quant/median"
+)
+
+// Dither selects the dithering algorithm applied when an image is reduced
+// to a palette.
+type Dither int
+
+const (
+	// NoDither maps each pixel to the nearest palette color.
+	NoDither Dither = iota
+	// FloydSteinberg applies Floyd-Steinberg error-diffusion dithering.
+	FloydSteinberg
+	// Halftone applies an ordered (Bayer matrix) dither.
+	Halftone
+)
+
+// bayer4x4 is the 4x4 ordered dithering threshold matrix used by Halftone,
+// normalized to [-0.5, 0.5).
+var bayer4x4 = [4][4]float64{
+	{0.0/16 - 0.5, 8.0/16 - 0.5, 2.0/16 - 0.5, 10.0/16 - 0.5},
+	{12.0/16 - 0.5, 4.0/16 - 0.5, 14.0/16 - 0.5, 6.0/16 - 0.5},
+	{3.0/16 - 0.5, 11.0/16 - 0.5, 1.0/16 - 0.5, 9.0/16 - 0.5},
+	{15.0/16 - 0.5, 7.0/16 - 0.5, 13.0/16 - 0.5, 5.0/16 - 0.5},
+}
+
+// quantize reduces img to a palette of at most colors colors, applying the
+// given dithering algorithm, and returns the resulting paletted image.
+func quantize(img image.Image, colors int, dither Dither) *image.Paletted {
+	bounds := img.Bounds()
+	palette := median.Quantizer(colors).Quantize(make(color.Palette, 0, colors), img)
+
+	paletted := image.NewPaletted(bounds, palette)
+	switch dither {
+	case FloydSteinberg:
+		draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+	case Halftone:
+		ditherHalftone(paletted, img, palette)
+	default:
+		draw.Draw(paletted, bounds, img, bounds.Min, draw.Src)
+	}
+	return paletted
+}
+
+// ditherHalftone applies an ordered Bayer-matrix dither, nudging each pixel's
+// luminance before mapping it to the nearest palette entry.
+func ditherHalftone(dst *image.Paletted, src image.Image, palette color.Palette) {
+	const amplitude = 32 // out of 255, how strongly the matrix perturbs each channel
+	bounds := src.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := src.At(x, y).RGBA()
+			bias := int32(bayer4x4[y%4][x%4] * amplitude)
+			nudged := color.RGBA64{
+				R: clampUint16(int32(r) + bias<<8),
+				G: clampUint16(int32(g) + bias<<8),
+				B: clampUint16(int32(b) + bias<<8),
+				A: uint16(a),
+			}
+			dst.Set(x, y, palette.Convert(nudged))
+		}
+	}
+}
+
+func clampUint16(v int32) uint16 {
+	if v < 0 {
+		return 0
+	}
+	if v > 0xffff {
+		return 0xffff
+	}
+	return uint16(v)
+}
+
+// encodeImage encodes img according to the given screenshot options,
+// quantizing and dithering it first if requested.
+func encodeImage(img image.Image, opts *ScreenshotOptions) ([]byte, error) {
+	format := opts.outputFormat
+	if format == "" {
+		format = FormatPNG
+		if opts.format == string(FormatJPEG) {
+			format = FormatJPEG
+		}
+	}
+
+	colors := opts.colors
+	if format == FormatGIF && colors == 0 {
+		colors = 256
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case FormatGIF:
+		paletted := quantize(img, colors, opts.dither)
+		if err := gif.Encode(&buf, paletted, nil); err != nil {
+			return nil, fmt.Errorf("failed to encode gif: %w", err)
+		}
+	case FormatPNG:
+		if colors > 0 {
+			img = quantize(img, colors, opts.dither)
+		}
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("failed to encode png: %w", err)
+		}
+	case FormatJPEG:
+		if colors > 0 {
+			img = quantize(img, colors, opts.dither)
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: int(opts.quality)}); err != nil {
+			return nil, fmt.Errorf("failed to encode jpeg: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output format: %q", format)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,49 @@
+package screentest
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// diffColor highlights changed pixels in the diff image.
+var diffColor = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+// ErrDimensionMismatch is returned by Diff when a and b have different
+// dimensions, so callers can tell a size change apart from other errors.
+var ErrDimensionMismatch = errors.New("image dimensions differ")
+
+// Diff compares a and b pixel-by-pixel and returns an image the same size
+// as both, with changed pixels highlighted in red, along with the number
+// of pixels that differ. a and b must have equal dimensions, or Diff
+// returns ErrDimensionMismatch.
+func Diff(a, b image.Image) (*image.RGBA, int, error) {
+	boundsA, boundsB := a.Bounds(), b.Bounds()
+	if boundsA.Dx() != boundsB.Dx() || boundsA.Dy() != boundsB.Dy() {
+		return nil, 0, fmt.Errorf("%w: %dx%d vs %dx%d",
+			ErrDimensionMismatch, boundsA.Dx(), boundsA.Dy(), boundsB.Dx(), boundsB.Dy())
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, boundsA.Dx(), boundsA.Dy()))
+	changed := 0
+	for y := 0; y < boundsA.Dy(); y++ {
+		for x := 0; x < boundsA.Dx(); x++ {
+			ca := a.At(boundsA.Min.X+x, boundsA.Min.Y+y)
+			cb := b.At(boundsB.Min.X+x, boundsB.Min.Y+y)
+			if colorsDiffer(ca, cb) {
+				changed++
+				out.Set(x, y, diffColor)
+			} else {
+				out.Set(x, y, ca)
+			}
+		}
+	}
+	return out, changed, nil
+}
+
+func colorsDiffer(a, b color.Color) bool {
+	ra, ga, ba, aa := a.RGBA()
+	rb, gb, bb, ab := b.RGBA()
+	return ra != rb || ga != gb || ba != bb || aa != ab
+}
@@ -0,0 +1,163 @@
+package screentest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/joeychilson/webpage"
+)
+
+// DefaultThreshold is the default number of changed pixels a TestCase may
+// have before it's reported as failing.
+const DefaultThreshold = 0
+
+// RunnerOptions is a struct that contains the options for the Runner
+type RunnerOptions struct {
+	cacheDir  string
+	threshold int
+}
+
+// RunnerOption defines a function to modify RunnerOptions
+type RunnerOption func(*RunnerOptions)
+
+// WithCacheDir sets the directory baseline captures (origins suffixed with
+// "::cache") are read from and written to.
+func WithCacheDir(dir string) RunnerOption {
+	return func(o *RunnerOptions) { o.cacheDir = dir }
+}
+
+// WithThreshold sets the number of changed pixels a TestCase may have
+// before it's reported as failing.
+func WithThreshold(n int) RunnerOption {
+	return func(o *RunnerOptions) { o.threshold = n }
+}
+
+// Runner drives TestCases through a Session and compares the results.
+type Runner struct {
+	session *webpage.Session
+	opts    *RunnerOptions
+}
+
+// NewRunner returns a Runner that captures screenshots using session.
+func NewRunner(session *webpage.Session, opts ...RunnerOption) *Runner {
+	o := &RunnerOptions{threshold: DefaultThreshold}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Runner{session: session, opts: o}
+}
+
+// Result is the outcome of running a single TestCase. Err is set instead of
+// ChangedPixels/Diff when the two sides couldn't be compared, such as a
+// dimension mismatch between captures.
+type Result struct {
+	TestCase      TestCase
+	ChangedPixels int
+	Threshold     int
+	Diff          *image.RGBA
+	Err           error
+}
+
+// Passed reports whether the TestCase could be compared and the number of
+// changed pixels was within threshold.
+func (r Result) Passed() bool { return r.Err == nil && r.ChangedPixels <= r.Threshold }
+
+// Run executes every test case and returns one Result per case. It stops
+// and returns the results gathered so far on the first capture error.
+func (r *Runner) Run(ctx context.Context, cases []TestCase) ([]Result, error) {
+	results := make([]Result, 0, len(cases))
+	for _, tc := range cases {
+		result, err := r.runCase(ctx, tc)
+		if err != nil {
+			return results, fmt.Errorf("test case comparing %q: %w", tc.Pathname, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r *Runner) runCase(ctx context.Context, tc TestCase) (Result, error) {
+	a, err := r.capture(ctx, tc, 0)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to capture %s: %w", tc.Origins[0], err)
+	}
+	b, err := r.capture(ctx, tc, 1)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to capture %s: %w", tc.Origins[1], err)
+	}
+
+	diff, changed, err := Diff(a, b)
+	if err != nil {
+		if errors.Is(err, ErrDimensionMismatch) {
+			return Result{TestCase: tc, Threshold: r.opts.threshold, Err: err}, nil
+		}
+		return Result{}, err
+	}
+	return Result{
+		TestCase:      tc,
+		ChangedPixels: changed,
+		Threshold:     r.opts.threshold,
+		Diff:          diff,
+	}, nil
+}
+
+// capture returns the rendered image for one side of a TestCase, reading
+// from (and populating) the baseline cache when that side uses "::cache".
+func (r *Runner) capture(ctx context.Context, tc TestCase, side int) (image.Image, error) {
+	if tc.Cached(side) && r.opts.cacheDir != "" {
+		path := r.cachePath(tc, side)
+		if data, err := os.ReadFile(path); err == nil {
+			img, err := png.Decode(bytes.NewReader(data))
+			if err == nil {
+				return img, nil
+			}
+		}
+	}
+
+	actions := tc.Actions
+
+	opts := []webpage.ScreenshotOption{webpage.WithFormat("png")}
+	if len(tc.Headers) > 0 {
+		opts = append(opts, webpage.WithHeaders(tc.Headers))
+	}
+	if tc.Capture == CaptureFullPage {
+		opts = append(opts, webpage.WithFullPage(true), webpage.WithViewport(tc.WindowWidth, 0))
+	} else if tc.WindowWidth > 0 || tc.WindowHeight > 0 {
+		opts = append(opts, webpage.WithViewport(tc.WindowWidth, tc.WindowHeight))
+	}
+
+	buf, err := r.session.Screenshot(ctx, tc.URL(side), actions, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := png.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	if tc.Cached(side) && r.opts.cacheDir != "" {
+		path := r.cachePath(tc, side)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			_ = os.WriteFile(path, buf, 0644)
+		}
+	}
+	return img, nil
+}
+
+// cachePath returns the on-disk baseline path for one side of a TestCase,
+// keyed by its rendered URL, window size and capture mode. Cases that differ
+// only in their headers or actions share the same entry.
+func (r *Runner) cachePath(tc TestCase, side int) string {
+	key := fmt.Sprintf("%s|%dx%d|%s", tc.URL(side), tc.WindowWidth, tc.WindowHeight, tc.Capture)
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(r.opts.cacheDir, hex.EncodeToString(sum[:])+".png")
+}
@@ -0,0 +1,140 @@
+// Package screentest drives script-defined visual regression tests,
+// comparing screenshots between two origins and reporting a per-pixel diff.
+package screentest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/joeychilson/webpage"
+)
+
+// Capture selects what portion of the page a TestCase screenshots.
+type Capture string
+
+const (
+	// CaptureViewport captures a single viewport.
+	CaptureViewport Capture = "viewport"
+	// CaptureFullPage captures the entire document height.
+	CaptureFullPage Capture = "fullpage"
+)
+
+// TestCase is a single comparison parsed from a test script.
+type TestCase struct {
+	Origins      [2]string
+	WindowWidth  int64
+	WindowHeight int64
+	Pathname     string
+	Headers      map[string]string
+	Capture      Capture
+	Actions      []webpage.Action
+}
+
+// URL returns the left (a) or right (b) side's URL, stripping any
+// "::cache" baseline marker from the origin.
+func (tc TestCase) URL(side int) string {
+	origin, _ := strings.CutSuffix(tc.Origins[side], "::cache")
+	return origin + tc.Pathname
+}
+
+// Cached reports whether side should be read from (and written to) the
+// on-disk baseline cache instead of always being re-rendered.
+func (tc TestCase) Cached(side int) bool {
+	_, cached := strings.CutSuffix(tc.Origins[side], "::cache")
+	return cached
+}
+
+// Parse reads a test script and returns its test cases. Directives are one
+// per line; a blank line ends the current test case. Recognized
+// directives: compare, windowsize, pathname, header, capture, click, wait.
+func Parse(r io.Reader) ([]TestCase, error) {
+	var cases []TestCase
+	var cur *TestCase
+
+	flush := func() {
+		if cur != nil {
+			if cur.Capture == "" {
+				cur.Capture = CaptureViewport
+			}
+			cases = append(cases, *cur)
+			cur = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		directive, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		if cur == nil {
+			cur = &TestCase{Headers: map[string]string{}}
+		}
+
+		switch directive {
+		case "compare":
+			origins := strings.Fields(rest)
+			if len(origins) != 2 {
+				return nil, fmt.Errorf("line %d: compare requires two origins, got %q", lineNum, rest)
+			}
+			cur.Origins = [2]string{origins[0], origins[1]}
+		case "windowsize":
+			w, h, ok := strings.Cut(rest, "x")
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid windowsize %q, want WxH", lineNum, rest)
+			}
+			width, err := strconv.ParseInt(w, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid windowsize width %q: %w", lineNum, w, err)
+			}
+			height, err := strconv.ParseInt(h, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid windowsize height %q: %w", lineNum, h, err)
+			}
+			cur.WindowWidth, cur.WindowHeight = width, height
+		case "pathname":
+			cur.Pathname = rest
+		case "header":
+			k, v, ok := strings.Cut(rest, ":")
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid header %q, want 'Key: Value'", lineNum, rest)
+			}
+			cur.Headers[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		case "capture":
+			switch Capture(rest) {
+			case CaptureFullPage, CaptureViewport:
+				cur.Capture = Capture(rest)
+			default:
+				return nil, fmt.Errorf("line %d: invalid capture mode %q", lineNum, rest)
+			}
+		case "click":
+			cur.Actions = append(cur.Actions, webpage.Click(rest))
+		case "wait":
+			cur.Actions = append(cur.Actions, webpage.WaitVisible(rest))
+		default:
+			return nil, fmt.Errorf("line %d: unknown directive %q", lineNum, directive)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read test script: %w", err)
+	}
+	flush()
+
+	for i, tc := range cases {
+		if tc.Origins[0] == "" || tc.Origins[1] == "" {
+			return nil, fmt.Errorf("test case %d: missing compare directive", i+1)
+		}
+	}
+	return cases, nil
+}
@@ -0,0 +1,336 @@
+package webpage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMaxConcurrent is the default number of renders a Server will
+	// run at the same time.
+	DefaultMaxConcurrent = 4
+	// DefaultRequestTimeout is the default per-request render timeout.
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the
+// webpage_request_duration_seconds histogram's buckets.
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// ServerOptions is a struct that contains the options for the Server
+type ServerOptions struct {
+	maxConcurrent  int
+	requestTimeout time.Duration
+}
+
+// ServerOption defines a function to modify ServerOptions
+type ServerOption func(*ServerOptions)
+
+// WithMaxConcurrent sets the number of renders the Server runs at once.
+// Requests beyond this limit queue until a slot frees up.
+func WithMaxConcurrent(n int) ServerOption {
+	return func(o *ServerOptions) { o.maxConcurrent = n }
+}
+
+// WithRequestTimeout sets the per-request render timeout.
+func WithRequestTimeout(d time.Duration) ServerOption {
+	return func(o *ServerOptions) { o.requestTimeout = d }
+}
+
+// Server exposes PDF and screenshot rendering over HTTP, backed by a single
+// reused Session so it can run as a long-lived rendering service.
+type Server struct {
+	session *Session
+	opts    *ServerOptions
+	sem     chan struct{}
+
+	requestsTotal       atomic.Int64
+	errorsTotal         atomic.Int64
+	latencySumMillis    atomic.Int64
+	latencyCount        atomic.Int64
+	latencyBucketCounts []atomic.Int64
+}
+
+// NewServer returns a Server that renders requests using session.
+func NewServer(session *Session, opts ...ServerOption) *Server {
+	o := &ServerOptions{
+		maxConcurrent:  DefaultMaxConcurrent,
+		requestTimeout: DefaultRequestTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Server{
+		session:             session,
+		opts:                o,
+		sem:                 make(chan struct{}, o.maxConcurrent),
+		latencyBucketCounts: make([]atomic.Int64, len(latencyBuckets)),
+	}
+}
+
+// Handler returns the http.Handler serving /pdf, /screenshot, /healthz, and
+// /metrics.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pdf", s.handlePDF)
+	mux.HandleFunc("/screenshot", s.handleScreenshot)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	return mux
+}
+
+// ListenAndServe starts the HTTP server on addr.
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// pdfRequest mirrors PDFOptions' orientation, background, scale, paper,
+// margin and page range fields for JSON/query-string decoding. The header
+// and footer templates, CSS page size, media emulation and wait options
+// are not exposed.
+type pdfRequest struct {
+	URL          string  `json:"url"`
+	Landscape    bool    `json:"landscape"`
+	Background   bool    `json:"background"`
+	Scale        float64 `json:"scale"`
+	PaperWidth   float64 `json:"paper_width"`
+	PaperHeight  float64 `json:"paper_height"`
+	MarginTop    float64 `json:"margin_top"`
+	MarginBottom float64 `json:"margin_bottom"`
+	MarginLeft   float64 `json:"margin_left"`
+	MarginRight  float64 `json:"margin_right"`
+	PageRanges   string  `json:"page_ranges"`
+}
+
+func pdfRequestFromQuery(q url.Values) pdfRequest {
+	return pdfRequest{
+		URL:          q.Get("url"),
+		Landscape:    queryBool(q, "landscape", false),
+		Background:   queryBool(q, "background", false),
+		Scale:        queryFloat(q, "scale", 1),
+		PaperWidth:   queryFloat(q, "paper_width", 8.5),
+		PaperHeight:  queryFloat(q, "paper_height", 11),
+		MarginTop:    queryFloat(q, "margin_top", 0.4),
+		MarginBottom: queryFloat(q, "margin_bottom", 0.4),
+		MarginLeft:   queryFloat(q, "margin_left", 0.4),
+		MarginRight:  queryFloat(q, "margin_right", 0.4),
+		PageRanges:   q.Get("page_ranges"),
+	}
+}
+
+func (req pdfRequest) options() []PDFOption {
+	return []PDFOption{
+		WithLandscape(req.Landscape),
+		WithBackground(req.Background),
+		WithScale(req.Scale),
+		WithPaperWidth(req.PaperWidth),
+		WithPaperHeight(req.PaperHeight),
+		WithMarginTop(req.MarginTop),
+		WithMarginBottom(req.MarginBottom),
+		WithMarginLeft(req.MarginLeft),
+		WithMarginRight(req.MarginRight),
+		WithPageRanges(req.PageRanges),
+	}
+}
+
+func (s *Server) handlePDF(w http.ResponseWriter, r *http.Request) {
+	defer s.observe(time.Now())
+
+	var req pdfRequest
+	switch r.Method {
+	case http.MethodGet:
+		req = pdfRequestFromQuery(r.URL.Query())
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.fail(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.URL == "" {
+		s.fail(w, http.StatusBadRequest, fmt.Errorf("url is required"))
+		return
+	}
+
+	if !s.acquire(r) {
+		s.fail(w, http.StatusServiceUnavailable, fmt.Errorf("too many concurrent requests"))
+		return
+	}
+	defer s.release()
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.requestTimeout)
+	defer cancel()
+
+	pdf, err := s.session.PDF(ctx, req.URL, nil, req.options()...)
+	if err != nil {
+		s.fail(w, http.StatusBadGateway, fmt.Errorf("failed to generate PDF: %w", err))
+		return
+	}
+
+	s.requestsTotal.Add(1)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Write(pdf)
+}
+
+// screenshotRequest mirrors ScreenshotOptions' format and quality for
+// JSON/query-string decoding. The other screenshot options are not exposed.
+type screenshotRequest struct {
+	URL     string `json:"url"`
+	Format  string `json:"format"`
+	Quality int64  `json:"quality"`
+}
+
+func screenshotRequestFromQuery(q url.Values) screenshotRequest {
+	return screenshotRequest{
+		URL:     q.Get("url"),
+		Format:  q.Get("format"),
+		Quality: int64(queryFloat(q, "quality", 100)),
+	}
+}
+
+func (req screenshotRequest) format() string {
+	if req.Format == "" {
+		return "png"
+	}
+	return req.Format
+}
+
+func (req screenshotRequest) options() []ScreenshotOption {
+	return []ScreenshotOption{
+		WithFormat(req.format()),
+		WithQuality(req.Quality),
+	}
+}
+
+func (s *Server) handleScreenshot(w http.ResponseWriter, r *http.Request) {
+	defer s.observe(time.Now())
+
+	var req screenshotRequest
+	switch r.Method {
+	case http.MethodGet:
+		req = screenshotRequestFromQuery(r.URL.Query())
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.fail(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.URL == "" {
+		s.fail(w, http.StatusBadRequest, fmt.Errorf("url is required"))
+		return
+	}
+
+	if !s.acquire(r) {
+		s.fail(w, http.StatusServiceUnavailable, fmt.Errorf("too many concurrent requests"))
+		return
+	}
+	defer s.release()
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.opts.requestTimeout)
+	defer cancel()
+
+	screenshot, err := s.session.Screenshot(ctx, req.URL, nil, req.options()...)
+	if err != nil {
+		s.fail(w, http.StatusBadGateway, fmt.Errorf("failed to capture screenshot: %w", err))
+		return
+	}
+
+	s.requestsTotal.Add(1)
+	w.Header().Set("Content-Type", "image/"+req.format())
+	w.Write(screenshot)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	requests := s.requestsTotal.Load()
+	errors := s.errorsTotal.Load()
+	latencySeconds := float64(s.latencySumMillis.Load()) / 1000
+	latencyCount := s.latencyCount.Load()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP webpage_requests_total Total number of successful render requests.\n")
+	fmt.Fprintf(w, "# TYPE webpage_requests_total counter\n")
+	fmt.Fprintf(w, "webpage_requests_total %d\n", requests)
+	fmt.Fprintf(w, "# HELP webpage_errors_total Total number of failed render requests.\n")
+	fmt.Fprintf(w, "# TYPE webpage_errors_total counter\n")
+	fmt.Fprintf(w, "webpage_errors_total %d\n", errors)
+	fmt.Fprintf(w, "# HELP webpage_request_duration_seconds Render request duration in seconds.\n")
+	fmt.Fprintf(w, "# TYPE webpage_request_duration_seconds histogram\n")
+	for i, bound := range latencyBuckets {
+		fmt.Fprintf(w, "webpage_request_duration_seconds_bucket{le=%q} %d\n",
+			strconv.FormatFloat(bound, 'g', -1, 64), s.latencyBucketCounts[i].Load())
+	}
+	fmt.Fprintf(w, "webpage_request_duration_seconds_bucket{le=\"+Inf\"} %d\n", latencyCount)
+	fmt.Fprintf(w, "webpage_request_duration_seconds_sum %f\n", latencySeconds)
+	fmt.Fprintf(w, "webpage_request_duration_seconds_count %d\n", latencyCount)
+}
+
+// acquire blocks until a render slot is free or the request is canceled,
+// returning false in the latter case so the caller can respond with a 503.
+func (s *Server) acquire(r *http.Request) bool {
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (s *Server) release() { <-s.sem }
+
+func (s *Server) fail(w http.ResponseWriter, status int, err error) {
+	s.errorsTotal.Add(1)
+	http.Error(w, err.Error(), status)
+}
+
+func (s *Server) observe(start time.Time) {
+	elapsed := time.Since(start)
+	s.latencySumMillis.Add(elapsed.Milliseconds())
+	s.latencyCount.Add(1)
+
+	seconds := elapsed.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			s.latencyBucketCounts[i].Add(1)
+		}
+	}
+}
+
+func queryBool(q url.Values, key string, def bool) bool {
+	v := q.Get(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+func queryFloat(q url.Values, key string, def float64) float64 {
+	v := q.Get(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
@@ -0,0 +1,234 @@
+package webpage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// Session is a persistent browser session that can be reused across
+// multiple PDF and screenshot captures, scripting an Action sequence
+// before each one. Each call to PDF or Screenshot runs in its own tab,
+// so a Session is safe to use concurrently.
+type Session struct {
+	browser *BrowserOptions
+
+	allocCancel context.CancelFunc
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// NewSession starts a browser process and returns a Session bound to it.
+// The browser runs until Close is called.
+func NewSession(ctx context.Context, opts ...BrowserOption) (*Session, error) {
+	browser := &BrowserOptions{
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(browser)
+	}
+
+	execOpts := []chromedp.ExecAllocatorOption{
+		chromedp.DisableGPU,
+		chromedp.NoSandbox,
+		chromedp.Headless,
+	}
+	if browser.userAgent != "" {
+		execOpts = append(execOpts, chromedp.UserAgent(browser.userAgent))
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, execOpts...)
+
+	taskCtx, cancel := chromedp.NewContext(allocCtx)
+	if err := chromedp.Run(taskCtx); err != nil {
+		cancel()
+		allocCancel()
+		return nil, fmt.Errorf("failed to start browser session: %w", err)
+	}
+
+	return &Session{
+		browser:     browser,
+		allocCancel: allocCancel,
+		ctx:         taskCtx,
+		cancel:      cancel,
+	}, nil
+}
+
+// Close shuts down the browser process and releases the session's resources.
+func (s *Session) Close() {
+	s.cancel()
+	s.allocCancel()
+}
+
+// watchCancel cancels cancel as soon as ctx is done, so a context derived
+// from the session's long-lived browser context still honors a caller's
+// deadline or cancellation. The returned stop func must be called once the
+// render finishes to release the goroutine.
+func watchCancel(ctx context.Context, cancel context.CancelFunc) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+// PDF navigates to url, runs actions, and returns the resulting PDF as a byte slice.
+func (s *Session) PDF(ctx context.Context, url string, actions []Action, opts ...PDFOption) ([]byte, error) {
+	pdfOpts := &PDFOptions{}
+	for _, opt := range opts {
+		opt(pdfOpts)
+	}
+
+	tabCtx, tabCancel := chromedp.NewContext(s.ctx)
+	defer tabCancel()
+
+	timeoutCtx, cancel := context.WithTimeout(tabCtx, s.browser.timeout)
+	defer cancel()
+	defer watchCancel(ctx, cancel)()
+
+	var pdfBuf []byte
+	tasks := chromedp.Tasks{chromedp.Navigate(url)}
+
+	switch pdfOpts.waitUntil {
+	case NetworkIdle:
+		tasks = append(tasks, waitNetworkIdle(networkIdleTimeout))
+	case DOMContentLoaded:
+		tasks = append(tasks, chromedp.WaitReady("body", chromedp.ByQuery))
+	}
+	if pdfOpts.waitSelector != "" {
+		tasks = append(tasks, chromedp.WaitVisible(pdfOpts.waitSelector, chromedp.ByQuery))
+	}
+	if pdfOpts.emulateMedia != "" {
+		tasks = append(tasks, emulation.SetEmulatedMedia().WithMedia(pdfOpts.emulateMedia))
+	}
+
+	for _, action := range actions {
+		tasks = append(tasks, chromedp.ActionFunc(action.Do))
+	}
+	tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		pdfBuf, _, err = page.
+			PrintToPDF().
+			WithLandscape(pdfOpts.landscape).
+			WithPrintBackground(pdfOpts.background).
+			WithScale(pdfOpts.scale).
+			WithPaperWidth(pdfOpts.pagerWidth).
+			WithPaperHeight(pdfOpts.pagerHeight).
+			WithMarginTop(pdfOpts.marginTop).
+			WithMarginBottom(pdfOpts.marginBottom).
+			WithMarginLeft(pdfOpts.marginLeft).
+			WithMarginRight(pdfOpts.marginRight).
+			WithPageRanges(pdfOpts.pageRanges).
+			WithDisplayHeaderFooter(pdfOpts.headerTemplate != "" || pdfOpts.footerTemplate != "").
+			WithHeaderTemplate(pdfOpts.headerTemplate).
+			WithFooterTemplate(pdfOpts.footerTemplate).
+			WithPreferCSSPageSize(pdfOpts.preferCSSPageSize).
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to generate PDF: %w", err)
+		}
+		return nil
+	}))
+
+	if err := chromedp.Run(timeoutCtx, tasks); err != nil {
+		return nil, fmt.Errorf("failed to execute tasks: %w", err)
+	}
+	return pdfBuf, nil
+}
+
+// Screenshot navigates to url, runs actions, and returns the resulting
+// screenshot as a byte slice, quantizing and encoding it per opts the same
+// way Webpage.Screenshot does. WithMaxTileHeight is ignored: Session has no
+// equivalent of Webpage.Screenshots, so a capture is always returned whole.
+func (s *Session) Screenshot(ctx context.Context, url string, actions []Action, opts ...ScreenshotOption) ([]byte, error) {
+	screenshotOpts := &ScreenshotOptions{
+		format:  "png",
+		quality: 100,
+	}
+	for _, opt := range opts {
+		opt(screenshotOpts)
+	}
+
+	tabCtx, tabCancel := chromedp.NewContext(s.ctx)
+	defer tabCancel()
+
+	timeoutCtx, cancel := context.WithTimeout(tabCtx, s.browser.timeout)
+	defer cancel()
+	defer watchCancel(ctx, cancel)()
+
+	var screenshotBuf []byte
+	tasks := chromedp.Tasks{}
+	if len(screenshotOpts.headers) > 0 {
+		headers := make(network.Headers, len(screenshotOpts.headers))
+		for k, v := range screenshotOpts.headers {
+			headers[k] = v
+		}
+		tasks = append(tasks,
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				return network.Enable().Do(ctx)
+			}),
+			network.SetExtraHTTPHeaders(headers),
+		)
+	}
+	tasks = append(tasks, chromedp.Navigate(url))
+	for _, action := range actions {
+		tasks = append(tasks, chromedp.ActionFunc(action.Do))
+	}
+
+	switch {
+	case screenshotOpts.fullPage:
+		width := screenshotOpts.viewportWidth
+		if width == 0 {
+			width = defaultViewportWidth
+		}
+		var height int64
+		tasks = append(tasks,
+			chromedp.Evaluate(`document.body.scrollHeight`, &height),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				return emulation.SetDeviceMetricsOverride(width, height, 1, false).Do(ctx)
+			}),
+		)
+	case screenshotOpts.viewportWidth > 0 || screenshotOpts.viewportHeight > 0:
+		tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+			return emulation.SetDeviceMetricsOverride(screenshotOpts.viewportWidth, screenshotOpts.viewportHeight, 1, false).Do(ctx)
+		}))
+	}
+
+	tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		screenshotBuf, err = page.CaptureScreenshot().
+			WithCaptureBeyondViewport(true).
+			WithFromSurface(true).
+			WithFormat(page.CaptureScreenshotFormat(screenshotOpts.format)).
+			WithQuality(screenshotOpts.quality).
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to capture screenshot: %w", err)
+		}
+		return nil
+	}))
+
+	if err := chromedp.Run(timeoutCtx, tasks); err != nil {
+		return nil, fmt.Errorf("failed to execute tasks: %w", err)
+	}
+
+	if screenshotOpts.colors == 0 && screenshotOpts.outputFormat == "" {
+		return screenshotBuf, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(screenshotBuf))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	return encodeImage(img, screenshotOpts)
+}
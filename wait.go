@@ -0,0 +1,50 @@
+package webpage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// networkIdleTimeout is how long the network must be quiet before
+// waitNetworkIdle considers navigation complete.
+const networkIdleTimeout = 500 * time.Millisecond
+
+// waitNetworkIdle blocks until there have been no in-flight network
+// requests for idle, or the context is canceled.
+func waitNetworkIdle(idle time.Duration) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var mu sync.Mutex
+		inflight := 0
+
+		timer := time.NewTimer(idle)
+		defer timer.Stop()
+
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch ev.(type) {
+			case *network.EventRequestWillBeSent:
+				mu.Lock()
+				inflight++
+				timer.Stop()
+				mu.Unlock()
+			case *network.EventLoadingFinished, *network.EventLoadingFailed:
+				mu.Lock()
+				inflight--
+				if inflight <= 0 {
+					timer.Reset(idle)
+				}
+				mu.Unlock()
+			}
+		})
+
+		select {
+		case <-timer.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
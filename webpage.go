@@ -1,10 +1,16 @@
 package webpage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"time"
 
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
 )
@@ -12,12 +18,21 @@ import (
 const (
 	// DefaultTimeout is the default timeout for the browser
 	DefaultTimeout = 30 * time.Second
+	// defaultViewportWidth is the viewport width used for full-page
+	// captures when WithViewport hasn't set one.
+	defaultViewportWidth = 1280
+	// maxSurfaceHeight is the tallest single capture Chrome will produce
+	// reliably; Screenshots tiles taller full-page captures into chunks
+	// no taller than this.
+	maxSurfaceHeight = 16384
 )
 
 // BrowserOptions is a struct that contains the options for the browser
 type BrowserOptions struct {
-	timeout   time.Duration
-	userAgent string
+	timeout          time.Duration
+	userAgent        string
+	extraHTTPHeaders map[string]string
+	cookies          []*network.CookieParam
 }
 
 // BrowserOption defines a function to modify BrowserOptions
@@ -33,6 +48,21 @@ func WithUserAgent(ua string) BrowserOption {
 	return func(o *BrowserOptions) { o.userAgent = ua }
 }
 
+// WithExtraHTTPHeaders sets headers sent with every request the browser
+// makes, useful for rendering pages behind auth. Only Webpage.PDF applies
+// them; Webpage.Screenshot, Webpage.Capture, Webpage.Screenshots and
+// Session ignore them.
+func WithExtraHTTPHeaders(headers map[string]string) BrowserOption {
+	return func(o *BrowserOptions) { o.extraHTTPHeaders = headers }
+}
+
+// WithCookies sets cookies on the browser before navigation. Only
+// Webpage.PDF sets them; Webpage.Screenshot, Webpage.Capture,
+// Webpage.Screenshots and Session ignore them.
+func WithCookies(cookies []*network.CookieParam) BrowserOption {
+	return func(o *BrowserOptions) { o.cookies = cookies }
+}
+
 // Webpage is a struct that contains the URL and Options for the webpage
 type Webpage struct {
 	url     string
@@ -50,18 +80,40 @@ func New(url string, opts ...BrowserOption) *Webpage {
 	return &Webpage{url: url, browser: browser}
 }
 
+// WaitUntil selects when a navigation is considered complete.
+type WaitUntil int
+
+const (
+	// Load waits for the page's load event, chromedp's default.
+	Load WaitUntil = iota
+	// DOMContentLoaded additionally waits for the document body to be
+	// ready. Navigation itself still waits for the load event, so this
+	// mode does not skip waiting on images and other subresources.
+	DOMContentLoaded
+	// NetworkIdle waits until there have been no in-flight network
+	// requests for a short grace period, for pages that fetch content
+	// after load fires.
+	NetworkIdle
+)
+
 // PDFOptions is a struct that contains the options for the PDF generation
 type PDFOptions struct {
-	landscape    bool
-	background   bool
-	scale        float64
-	pagerWidth   float64
-	pagerHeight  float64
-	marginTop    float64
-	marginBottom float64
-	marginLeft   float64
-	marginRight  float64
-	pageRanges   string
+	landscape         bool
+	background        bool
+	scale             float64
+	pagerWidth        float64
+	pagerHeight       float64
+	marginTop         float64
+	marginBottom      float64
+	marginLeft        float64
+	marginRight       float64
+	pageRanges        string
+	headerTemplate    string
+	footerTemplate    string
+	preferCSSPageSize bool
+	emulateMedia      string
+	waitUntil         WaitUntil
+	waitSelector      string
 }
 
 // PDFOption defines a function to modify PDFOptions
@@ -117,6 +169,42 @@ func WithPageRanges(ranges string) PDFOption {
 	return func(o *PDFOptions) { o.pageRanges = ranges }
 }
 
+// WithHeaderTemplate sets the HTML template for the page header. Requires
+// display of the header and footer, so it implies WithFooterTemplate("")
+// unless a footer template is also set.
+func WithHeaderTemplate(html string) PDFOption {
+	return func(o *PDFOptions) { o.headerTemplate = html }
+}
+
+// WithFooterTemplate sets the HTML template for the page footer.
+func WithFooterTemplate(html string) PDFOption {
+	return func(o *PDFOptions) { o.footerTemplate = html }
+}
+
+// WithPreferCSSPageSize honors any @page size declared in the page's CSS
+// over WithPaperWidth/WithPaperHeight.
+func WithPreferCSSPageSize(enable bool) PDFOption {
+	return func(o *PDFOptions) { o.preferCSSPageSize = enable }
+}
+
+// WithEmulateMedia emulates the given CSS media type ("print" or "screen")
+// before generating the PDF.
+func WithEmulateMedia(media string) PDFOption {
+	return func(o *PDFOptions) { o.emulateMedia = media }
+}
+
+// WithWaitUntil sets when the navigation is considered complete, before
+// the PDF is captured.
+func WithWaitUntil(waitUntil WaitUntil) PDFOption {
+	return func(o *PDFOptions) { o.waitUntil = waitUntil }
+}
+
+// WithWaitSelector waits for selector to be visible before the PDF is
+// captured, for content that renders after the navigation completes.
+func WithWaitSelector(selector string) PDFOption {
+	return func(o *PDFOptions) { o.waitSelector = selector }
+}
+
 // PDF returns the PDF of the webpage as a byte slice
 func (w *Webpage) PDF(ctx context.Context, opts ...PDFOption) ([]byte, error) {
 	pdfOpts := &PDFOptions{}
@@ -144,41 +232,103 @@ func (w *Webpage) PDF(ctx context.Context, opts ...PDFOption) ([]byte, error) {
 	defer cancel()
 
 	var pdfBuf []byte
-	tasks := chromedp.Tasks{
-		chromedp.Navigate(w.url),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			var err error
+	tasks := chromedp.Tasks{}
 
-			pdfBuf, _, err = page.
-				PrintToPDF().
-				WithLandscape(pdfOpts.landscape).
-				WithPrintBackground(pdfOpts.background).
-				WithScale(pdfOpts.scale).
-				WithPaperWidth(pdfOpts.pagerWidth).
-				WithPaperHeight(pdfOpts.pagerHeight).
-				WithMarginTop(pdfOpts.marginTop).
-				WithMarginBottom(pdfOpts.marginBottom).
-				WithMarginLeft(pdfOpts.marginLeft).
-				WithMarginRight(pdfOpts.marginRight).
-				WithPageRanges(pdfOpts.pageRanges).
+	if len(w.browser.extraHTTPHeaders) > 0 || len(w.browser.cookies) > 0 {
+		tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+			return network.Enable().Do(ctx)
+		}))
+	}
+	if len(w.browser.extraHTTPHeaders) > 0 {
+		headers := make(network.Headers, len(w.browser.extraHTTPHeaders))
+		for k, v := range w.browser.extraHTTPHeaders {
+			headers[k] = v
+		}
+		tasks = append(tasks, network.SetExtraHTTPHeaders(headers))
+	}
+	for _, cookie := range w.browser.cookies {
+		cookie := cookie
+		tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+			return network.SetCookie(cookie.Name, cookie.Value).
+				WithDomain(cookie.Domain).
+				WithPath(cookie.Path).
+				WithSecure(cookie.Secure).
+				WithHTTPOnly(cookie.HTTPOnly).
 				Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to generate PDF: %w", err)
-			}
-			return nil
-		}),
+		}))
 	}
 
+	tasks = append(tasks, chromedp.Navigate(w.url))
+
+	switch pdfOpts.waitUntil {
+	case NetworkIdle:
+		tasks = append(tasks, waitNetworkIdle(networkIdleTimeout))
+	case DOMContentLoaded:
+		tasks = append(tasks, chromedp.WaitReady("body", chromedp.ByQuery))
+	}
+	if pdfOpts.waitSelector != "" {
+		tasks = append(tasks, chromedp.WaitVisible(pdfOpts.waitSelector, chromedp.ByQuery))
+	}
+	if pdfOpts.emulateMedia != "" {
+		tasks = append(tasks, emulation.SetEmulatedMedia().WithMedia(pdfOpts.emulateMedia))
+	}
+
+	tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+
+		pdfBuf, _, err = page.
+			PrintToPDF().
+			WithLandscape(pdfOpts.landscape).
+			WithPrintBackground(pdfOpts.background).
+			WithScale(pdfOpts.scale).
+			WithPaperWidth(pdfOpts.pagerWidth).
+			WithPaperHeight(pdfOpts.pagerHeight).
+			WithMarginTop(pdfOpts.marginTop).
+			WithMarginBottom(pdfOpts.marginBottom).
+			WithMarginLeft(pdfOpts.marginLeft).
+			WithMarginRight(pdfOpts.marginRight).
+			WithPageRanges(pdfOpts.pageRanges).
+			WithDisplayHeaderFooter(pdfOpts.headerTemplate != "" || pdfOpts.footerTemplate != "").
+			WithHeaderTemplate(pdfOpts.headerTemplate).
+			WithFooterTemplate(pdfOpts.footerTemplate).
+			WithPreferCSSPageSize(pdfOpts.preferCSSPageSize).
+			Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to generate PDF: %w", err)
+		}
+		return nil
+	}))
+
 	if err := chromedp.Run(timeoutCtx, tasks); err != nil {
 		return nil, fmt.Errorf("failed to execute tasks: %w", err)
 	}
 	return pdfBuf, nil
 }
 
+// OutputFormat is the encoding used for a screenshot.
+type OutputFormat string
+
+const (
+	// FormatPNG encodes the screenshot as PNG.
+	FormatPNG OutputFormat = "png"
+	// FormatJPEG encodes the screenshot as JPEG.
+	FormatJPEG OutputFormat = "jpeg"
+	// FormatGIF encodes the screenshot as a palette-indexed GIF.
+	FormatGIF OutputFormat = "gif"
+)
+
 // ScreenshotOptions is a struct that contains the options for the screenshot
 type ScreenshotOptions struct {
-	format  string
-	quality int64
+	format         string
+	quality        int64
+	colors         int
+	dither         Dither
+	outputFormat   OutputFormat
+	fullPage       bool
+	viewportWidth  int64
+	viewportHeight int64
+	maxTileHeight  int64
+	headers        map[string]string
 }
 
 // ScreenshotOption defines a function to modify ScreenshotOptions
@@ -194,6 +344,53 @@ func WithQuality(quality int64) ScreenshotOption {
 	return func(o *ScreenshotOptions) { o.quality = quality }
 }
 
+// WithColors reduces the screenshot to a palette of at most n colors (2-256).
+func WithColors(n int) ScreenshotOption {
+	return func(o *ScreenshotOptions) { o.colors = n }
+}
+
+// WithDither sets the dithering algorithm used when reducing to a palette.
+func WithDither(dither Dither) ScreenshotOption {
+	return func(o *ScreenshotOptions) { o.dither = dither }
+}
+
+// WithOutputFormat sets the encoding of the screenshot, overriding format
+// for cases (like GIF) that require post-processing Chrome's raw capture.
+func WithOutputFormat(format OutputFormat) ScreenshotOption {
+	return func(o *ScreenshotOptions) { o.outputFormat = format }
+}
+
+// WithFullPage captures the entire document height instead of a single
+// viewport, overriding the height passed to WithViewport.
+func WithFullPage(enable bool) ScreenshotOption {
+	return func(o *ScreenshotOptions) { o.fullPage = enable }
+}
+
+// WithViewport sets the browser viewport size used for the capture. Passing
+// height=0 together with WithFullPage captures the full scroll height.
+func WithViewport(width, height int64) ScreenshotOption {
+	return func(o *ScreenshotOptions) {
+		o.viewportWidth = width
+		o.viewportHeight = height
+	}
+}
+
+// WithMaxTileHeight caps each tile Screenshots captures at maxPixels tall
+// instead of the default maxSurfaceHeight, so no single capture exceeds
+// Chrome's screenshot surface size limit on very long pages.
+func WithMaxTileHeight(maxPixels int64) ScreenshotOption {
+	return func(o *ScreenshotOptions) { o.maxTileHeight = maxPixels }
+}
+
+// WithHeaders sets extra HTTP headers sent with the page's first document
+// request. Unlike SetExtraHeaders, which only affects requests triggered
+// after navigation, these headers are applied before Navigate runs. Only
+// Session.Screenshot applies them; Webpage.Screenshot, Webpage.Capture and
+// Webpage.Screenshots ignore them.
+func WithHeaders(headers map[string]string) ScreenshotOption {
+	return func(o *ScreenshotOptions) { o.headers = headers }
+}
+
 // Screenshot returns the screenshot of the webpage as a byte slice
 func (w *Webpage) Screenshot(ctx context.Context, opts ...ScreenshotOption) ([]byte, error) {
 	screenshotOpts := &ScreenshotOptions{
@@ -204,6 +401,43 @@ func (w *Webpage) Screenshot(ctx context.Context, opts ...ScreenshotOption) ([]b
 		opt(screenshotOpts)
 	}
 
+	if screenshotOpts.colors == 0 && screenshotOpts.outputFormat == "" {
+		return w.captureRaw(ctx, screenshotOpts)
+	}
+
+	img, err := w.Capture(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return encodeImage(img, screenshotOpts)
+}
+
+// Capture returns the decoded screenshot of the webpage as an image.Image,
+// before any format encoding, so callers can apply their own transforms.
+func (w *Webpage) Capture(ctx context.Context, opts ...ScreenshotOption) (image.Image, error) {
+	screenshotOpts := &ScreenshotOptions{
+		format:  "png",
+		quality: 100,
+	}
+	for _, opt := range opts {
+		opt(screenshotOpts)
+	}
+
+	buf, err := w.captureRaw(ctx, screenshotOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	return img, nil
+}
+
+// captureRaw drives Chrome to capture the screenshot and returns its raw
+// encoded bytes in screenshotOpts.format.
+func (w *Webpage) captureRaw(ctx context.Context, screenshotOpts *ScreenshotOptions) ([]byte, error) {
 	execOpts := []chromedp.ExecAllocatorOption{
 		chromedp.DisableGPU,
 		chromedp.NoSandbox,
@@ -226,6 +460,28 @@ func (w *Webpage) Screenshot(ctx context.Context, opts ...ScreenshotOption) ([]b
 	var screenshotBuf []byte
 	tasks := chromedp.Tasks{
 		chromedp.Navigate(w.url),
+	}
+
+	switch {
+	case screenshotOpts.fullPage:
+		width := screenshotOpts.viewportWidth
+		if width == 0 {
+			width = defaultViewportWidth
+		}
+		var height int64
+		tasks = append(tasks,
+			chromedp.Evaluate(`document.body.scrollHeight`, &height),
+			chromedp.ActionFunc(func(ctx context.Context) error {
+				return emulation.SetDeviceMetricsOverride(width, height, 1, false).Do(ctx)
+			}),
+		)
+	case screenshotOpts.viewportWidth > 0 || screenshotOpts.viewportHeight > 0:
+		tasks = append(tasks, chromedp.ActionFunc(func(ctx context.Context) error {
+			return emulation.SetDeviceMetricsOverride(screenshotOpts.viewportWidth, screenshotOpts.viewportHeight, 1, false).Do(ctx)
+		}))
+	}
+
+	tasks = append(tasks,
 		chromedp.ActionFunc(func(ctx context.Context) error {
 			var err error
 			screenshotBuf, err = page.CaptureScreenshot().
@@ -239,7 +495,7 @@ func (w *Webpage) Screenshot(ctx context.Context, opts ...ScreenshotOption) ([]b
 			}
 			return nil
 		}),
-	}
+	)
 
 	if err := chromedp.Run(timeoutCtx, tasks); err != nil {
 		return nil, fmt.Errorf("failed to execute tasks: %w", err)